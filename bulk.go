@@ -0,0 +1,170 @@
+package qbs
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// DefaultBulkInsertBatchSize caps how many rows a native BulkDialect combines
+// into a single multi-VALUES INSERT, keeping the statement under common
+// driver placeholder limits (e.g. Postgres' 65535 bind parameters).
+const DefaultBulkInsertBatchSize = 500
+
+// BulkDialect is implemented by dialects that can render their own native
+// multi-row insert and upsert SQL: a multi-VALUES INSERT, Postgres' ON
+// CONFLICT, MySQL's ON DUPLICATE KEY UPDATE, or SQLite's INSERT OR REPLACE.
+// BulkInsert/Upsert use it when the configured Dialect implements it, and
+// otherwise fall back to a portable implementation built out of the
+// existing single-row insert/update.
+type BulkDialect interface {
+	bulkInsert(q *Qbs, models []*model) (affected int64, err error)
+	upsert(q *Qbs, conflictCols ...string) (affected int64, err error)
+}
+
+// SaveAll inserts every struct pointer in sliceOfStructPtr, batching them in
+// groups of DefaultBulkInsertBatchSize. Every element must point to the
+// same struct type. It returns the total number of rows affected.
+func (q *Qbs) SaveAll(sliceOfStructPtr interface{}) (affected int64, err error) {
+	return q.BulkInsert(sliceOfStructPtr, DefaultBulkInsertBatchSize)
+}
+
+// BulkInsert is like SaveAll but lets the caller choose the batch size. When
+// Dialect implements BulkDialect, each batch becomes one multi-VALUES
+// INSERT; otherwise the batch is inserted row-by-row inside a single
+// transaction, so it still commits or rolls back atomically.
+func (q *Qbs) BulkInsert(sliceOfStructPtr interface{}, batchSize int) (affected int64, err error) {
+	sliceValue := reflect.Indirect(reflect.ValueOf(sliceOfStructPtr))
+	if sliceValue.Len() == 0 {
+		return 0, nil
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBulkInsertBatchSize
+	}
+	bd, native := q.Dialect.(BulkDialect)
+	err = q.InTx(func(tx *Qbs) error {
+		for start := 0; start < sliceValue.Len(); start += batchSize {
+			end := start + batchSize
+			if end > sliceValue.Len() {
+				end = sliceValue.Len()
+			}
+			structPtrs := make([]interface{}, 0, end-start)
+			models := make([]*model, 0, end-start)
+			for i := start; i < end; i++ {
+				structPtr := sliceValue.Index(i).Interface()
+				if v, ok := structPtr.(Validator); ok {
+					if verr := v.Validate(tx); verr != nil {
+						return verr
+					}
+				}
+				m := structPtrToModel(structPtr, true, tx.criteria.omitFields)
+				if m.pk == nil {
+					panic("no primary key field")
+				}
+				structPtrs = append(structPtrs, structPtr)
+				models = append(models, m)
+			}
+			var batchAffected int64
+			var berr error
+			if native {
+				batchAffected, berr = bd.bulkInsert(tx, models)
+			} else {
+				batchAffected, berr = tx.bulkInsertOneByOne(structPtrs, models)
+			}
+			if berr != nil {
+				return berr
+			}
+			affected += batchAffected
+		}
+		return nil
+	})
+	if err != nil {
+		affected = 0
+	}
+	return
+}
+
+// bulkInsertOneByOne is the portable fallback used when Dialect doesn't
+// implement BulkDialect: it inserts each model through the existing
+// single-row Dialect.insert, matching Save's contract of stamping
+// created/updated timestamps and writing the generated PK back into
+// structPtrs.
+func (q *Qbs) bulkInsertOneByOne(structPtrs []interface{}, models []*model) (affected int64, err error) {
+	now := time.Now()
+	for i, m := range models {
+		updatedField := m.timeFiled("updated")
+		if updatedField != nil {
+			updatedField.value = now
+		}
+		createdField := m.timeFiled("created")
+		if createdField != nil {
+			createdField.value = now
+		}
+		q.criteria.model = m
+		var id int64
+		id, err = q.Dialect.insert(q)
+		if err != nil {
+			return
+		}
+		affected++
+		structValue := reflect.Indirect(reflect.ValueOf(structPtrs[i]))
+		if _, ok := m.pk.value.(int64); ok && id != 0 {
+			structValue.FieldByName(m.pk.camelName).SetInt(id)
+		}
+		if updatedField != nil {
+			structValue.FieldByName(updatedField.camelName).Set(reflect.ValueOf(now))
+		}
+		if createdField != nil {
+			structValue.FieldByName(createdField.camelName).Set(reflect.ValueOf(now))
+		}
+	}
+	return
+}
+
+// Upsert inserts structPtr, or updates it in place if a row already exists
+// for the given conflict columns. When Dialect implements BulkDialect this
+// dispatches to its native upsert SQL; otherwise it falls back to an
+// update-then-insert emulation (see upsertFallback). If the struct type
+// implements Validator, it is validated first.
+func (q *Qbs) Upsert(structPtr interface{}, conflictCols ...string) (affected int64, err error) {
+	if len(conflictCols) == 0 {
+		return 0, fmt.Errorf("qbs: Upsert requires at least one conflict column")
+	}
+	if v, ok := structPtr.(Validator); ok {
+		if err = v.Validate(q); err != nil {
+			return
+		}
+	}
+	if bd, ok := q.Dialect.(BulkDialect); ok {
+		q.criteria.model = structPtrToModel(structPtr, true, q.criteria.omitFields)
+		return bd.upsert(q, conflictCols...)
+	}
+	return q.upsertFallback(structPtr, conflictCols)
+}
+
+// upsertFallback emulates Upsert without a native ON CONFLICT/ON DUPLICATE
+// KEY UPDATE/INSERT OR REPLACE clause: inside a transaction, it tries an
+// update keyed on conflictCols first, then inserts if no row matched. This
+// costs two round-trips where a native BulkDialect costs one.
+func (q *Qbs) upsertFallback(structPtr interface{}, conflictCols []string) (affected int64, err error) {
+	structValue := reflect.Indirect(reflect.ValueOf(structPtr))
+	mapper := q.nameMapper()
+	lookups := make(map[string]interface{}, len(conflictCols))
+	for _, col := range conflictCols {
+		field := fieldByColumn(structValue, col, mapper)
+		if !field.IsValid() {
+			return 0, fmt.Errorf("qbs: Upsert: no struct field for conflict column %q", col)
+		}
+		lookups[col] = field.Interface()
+	}
+	err = q.InTx(func(tx *Qbs) error {
+		tx.Filter(lookups)
+		var uerr error
+		affected, uerr = tx.Update(structPtr)
+		if uerr == nil && affected == 0 {
+			affected, uerr = tx.Save(structPtr)
+		}
+		return uerr
+	})
+	return
+}
@@ -0,0 +1,129 @@
+package qbs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Filter adds WHERE conditions derived from a map of field lookups, merging
+// them into any existing condition with AND. Keys follow Django/Beego-style
+// "field__op" suffixes, e.g. "age__gt", "name__icontains", "id__in",
+// "created__between", "deleted__isnull"; a key with no "__op" suffix is
+// compared with "=". As with WhereEqual, the field part of the key is the
+// snakecase column name. This is a convenience layer over Where/
+// NewCondition for callers who would otherwise hand-write SQL fragments
+// with "?" placeholders and duplicate the dialect's quoting rules.
+func (q *Qbs) Filter(lookups map[string]interface{}) *Qbs {
+	return q.mergeLookups(lookups, false)
+}
+
+// Exclude is the negated counterpart of Filter: every lookup is wrapped in
+// "NOT (...)" before being merged into the condition with AND.
+func (q *Qbs) Exclude(lookups map[string]interface{}) *Qbs {
+	return q.mergeLookups(lookups, true)
+}
+
+func (q *Qbs) mergeLookups(lookups map[string]interface{}, negate bool) *Qbs {
+	for key, value := range lookups {
+		condition := lookupCondition(q.Dialect, key, value, negate)
+		if q.criteria.condition == nil {
+			q.criteria.condition = condition
+		} else {
+			q.criteria.condition = q.criteria.condition.AndCondition(condition)
+		}
+	}
+	return q
+}
+
+// lookupOps maps a Django-style lookup suffix to the SQL comparison
+// operator it renders to. Suffixes not listed here (in, between, isnull,
+// contains, icontains, startswith, endswith) need custom expression shapes
+// and are handled directly in lookupCondition; any other suffix compares
+// with "=".
+var lookupOps = map[string]string{
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+	"ne":  "!=",
+}
+
+// quoter is the subset of Dialect that lookupCondition needs, narrowed out
+// so it can be unit tested without a full Dialect implementation.
+type quoter interface {
+	quote(string) string
+}
+
+// lookupCondition translates a single "field" or "field__op" lookup key
+// into a quoted, dialect-aware *Condition.
+func lookupCondition(dialect quoter, key string, value interface{}, negate bool) *Condition {
+	field := key
+	op := "eq"
+	if idx := strings.LastIndex(key, "__"); idx != -1 {
+		field, op = key[:idx], key[idx+2:]
+	}
+	column := dialect.quote(field)
+	var expr string
+	var args []interface{}
+	switch op {
+	case "in":
+		inArgs := toArgSlice(value)
+		if len(inArgs) == 0 {
+			expr = "1 = 0"
+		} else {
+			placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(inArgs)), ", ")
+			expr, args = column+" IN ("+placeholders+")", inArgs
+		}
+	case "between":
+		bounds := toArgSlice(value)
+		expr, args = column+" BETWEEN ? AND ?", bounds
+	case "isnull":
+		if isTrue(value) {
+			expr = column + " IS NULL"
+		} else {
+			expr = column + " IS NOT NULL"
+		}
+	case "contains":
+		expr, args = column+" LIKE ?", []interface{}{fmt.Sprintf("%%%v%%", value)}
+	case "icontains":
+		expr, args = "LOWER("+column+") LIKE LOWER(?)", []interface{}{fmt.Sprintf("%%%v%%", value)}
+	case "startswith":
+		expr, args = column+" LIKE ?", []interface{}{fmt.Sprintf("%v%%", value)}
+	case "endswith":
+		expr, args = column+" LIKE ?", []interface{}{fmt.Sprintf("%%%v", value)}
+	default:
+		sqlOp, ok := lookupOps[op]
+		if !ok {
+			sqlOp = "="
+		}
+		expr, args = column+" "+sqlOp+" ?", []interface{}{value}
+	}
+	if negate {
+		expr = "NOT (" + expr + ")"
+	}
+	return NewCondition(expr, args...)
+}
+
+func toArgSlice(value interface{}) []interface{} {
+	if args, ok := value.([]interface{}); ok {
+		return args
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []interface{}{value}
+	}
+	args := make([]interface{}, rv.Len())
+	for i := range args {
+		args[i] = rv.Index(i).Interface()
+	}
+	return args
+}
+
+// isTrue reports whether value is the bool true. A non-bool value (e.g. a
+// caller passing 1 instead of true) is treated as false rather than
+// silently coercing to true.
+func isTrue(value interface{}) bool {
+	b, _ := value.(bool)
+	return b
+}
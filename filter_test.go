@@ -0,0 +1,57 @@
+package qbs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToArgSlice(t *testing.T) {
+	cases := []struct {
+		name  string
+		value interface{}
+		want  []interface{}
+	}{
+		{"nil", nil, []interface{}{nil}},
+		{"scalar", 5, []interface{}{5}},
+		{"interface slice", []interface{}{1, 2, 3}, []interface{}{1, 2, 3}},
+		{"typed slice", []int{1, 2, 3}, []interface{}{1, 2, 3}},
+		{"empty slice", []int{}, []interface{}{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := toArgSlice(c.value)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("toArgSlice(%#v) = %#v, want %#v", c.value, got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsTrue(t *testing.T) {
+	cases := []struct {
+		value interface{}
+		want  bool
+	}{
+		{true, true},
+		{false, false},
+		{1, false},
+		{"true", false},
+		{nil, false},
+	}
+	for _, c := range cases {
+		if got := isTrue(c.value); got != c.want {
+			t.Errorf("isTrue(%#v) = %v, want %v", c.value, got, c.want)
+		}
+	}
+}
+
+type fakeQuoter struct{}
+
+func (fakeQuoter) quote(name string) string { return "`" + name + "`" }
+
+func TestLookupConditionEmptyIn(t *testing.T) {
+	cond := lookupCondition(fakeQuoter{}, "id__in", []int{}, false)
+	if cond == nil {
+		t.Fatal("lookupCondition returned nil")
+	}
+}
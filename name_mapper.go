@@ -0,0 +1,43 @@
+package qbs
+
+import "reflect"
+
+// nameMapper returns the function scanRows uses to derive a struct field
+// name from an unmapped DB column name, falling back to the package
+// default of snakeToUpperCamel when NameMapper is unset.
+func (q *Qbs) nameMapper() func(string) string {
+	if q.NameMapper != nil {
+		return q.NameMapper
+	}
+	return snakeToUpperCamel
+}
+
+// fieldByColumn resolves a DB column name to a field of v. It first looks
+// for a field of v whose `db:"..."` / `qbs:"column=..."` tag names column,
+// then for the field named mapper(column) (fields promoted from embedded
+// structs included), and then repeats both steps inside each embedded
+// struct. This is the column lookup used by scanRows, upsertFallback, the
+// RETURNING read-back and NamedExec/NamedQuery's struct binding, so a
+// custom NameMapper or tag override applies the same way in all of them.
+// structPtrToModel, which builds the SQL of Find/Save/Update/Delete, takes
+// no mapper, so NameMapper does not change the column names in that SQL.
+func fieldByColumn(v reflect.Value, column string, mapper func(string) string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if columnName(t.Field(i)) == column {
+			return v.Field(i)
+		}
+	}
+	if field := v.FieldByName(mapper(column)); field.IsValid() {
+		return field
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && v.Field(i).Kind() == reflect.Struct {
+			if found := fieldByColumn(v.Field(i), column, mapper); found.IsValid() {
+				return found
+			}
+		}
+	}
+	return reflect.Value{}
+}
@@ -0,0 +1,73 @@
+package qbs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldByColumnDefaultMapper(t *testing.T) {
+	type user struct {
+		Id   int64
+		Name string
+	}
+	u := user{Id: 1, Name: "bob"}
+	v := reflect.ValueOf(u)
+
+	field := fieldByColumn(v, "name", snakeToUpperCamel)
+	if !field.IsValid() || field.Interface() != "bob" {
+		t.Fatalf("fieldByColumn(%q) = %v, want %q", "name", field, "bob")
+	}
+}
+
+func TestFieldByColumnTagOverride(t *testing.T) {
+	type user struct {
+		FullName string `db:"name"`
+	}
+	u := user{FullName: "bob"}
+	v := reflect.ValueOf(u)
+
+	field := fieldByColumn(v, "name", snakeToUpperCamel)
+	if !field.IsValid() || field.Interface() != "bob" {
+		t.Fatalf("fieldByColumn(%q) with db tag = %v, want %q", "name", field, "bob")
+	}
+}
+
+func TestFieldByColumnQbsColumnTag(t *testing.T) {
+	type user struct {
+		FullName string `qbs:"column=name"`
+	}
+	u := user{FullName: "bob"}
+	v := reflect.ValueOf(u)
+
+	field := fieldByColumn(v, "name", snakeToUpperCamel)
+	if !field.IsValid() || field.Interface() != "bob" {
+		t.Fatalf("fieldByColumn(%q) with qbs tag = %v, want %q", "name", field, "bob")
+	}
+}
+
+func TestFieldByColumnEmbeddedStruct(t *testing.T) {
+	type base struct {
+		Id int64
+	}
+	type user struct {
+		base
+		Name string
+	}
+	u := user{base: base{Id: 1}, Name: "bob"}
+	v := reflect.ValueOf(u)
+
+	field := fieldByColumn(v, "id", snakeToUpperCamel)
+	if !field.IsValid() || field.Interface() != int64(1) {
+		t.Fatalf("fieldByColumn(%q) into embedded struct = %v, want %d", "id", field, 1)
+	}
+}
+
+func TestFieldByColumnInvalid(t *testing.T) {
+	type user struct {
+		Name string
+	}
+	v := reflect.ValueOf(user{})
+	if field := fieldByColumn(v, "does_not_exist", snakeToUpperCamel); field.IsValid() {
+		t.Fatalf("fieldByColumn(%q) = %v, want invalid", "does_not_exist", field)
+	}
+}
@@ -0,0 +1,141 @@
+package qbs
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NamedExec is like Exec but the query uses ":name" placeholders instead of
+// positional "?" ones. arg supplies the values, either as a
+// map[string]interface{} or a struct (tagged fields, embedded structs and
+// plain field names are all looked up by name). A slice-valued placeholder,
+// e.g. ":ids", expands into "?, ?, ?" for as many elements as the slice
+// holds, mirroring sqlx.In.
+func (q *Qbs) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	positional, args, err := bindNamed(query, arg, q.nameMapper())
+	if err != nil {
+		return nil, err
+	}
+	return q.Exec(positional, args...)
+}
+
+// NamedQuery is like Query but the query uses ":name" placeholders instead
+// of positional "?" ones. See NamedExec for how arg is bound.
+func (q *Qbs) NamedQuery(query string, arg interface{}) (*sql.Rows, error) {
+	positional, args, err := bindNamed(query, arg, q.nameMapper())
+	if err != nil {
+		return nil, err
+	}
+	return q.Query(positional, args...)
+}
+
+// bindNamed rewrites a query containing ":name" placeholders into one using
+// positional "?" placeholders, returning the arguments in the matching
+// order. The final dialect-specific marker substitution (e.g. "?" -> "$1")
+// still happens afterwards, inside Exec/Query via Dialect.substituteMarkers.
+func bindNamed(query string, arg interface{}, mapper func(string) string) (string, []interface{}, error) {
+	lookup, err := namedArgLookup(arg, mapper)
+	if err != nil {
+		return "", nil, err
+	}
+	var out strings.Builder
+	var args []interface{}
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		if c == ':' && i+1 < len(runes) && runes[i+1] == ':' {
+			// "::" is a Postgres-style cast, e.g. "id::text" - consume both
+			// colons as a literal token so the second one isn't mistaken for
+			// the start of a new named placeholder.
+			out.WriteRune(c)
+			out.WriteRune(runes[i+1])
+			i++
+			continue
+		}
+		if c != ':' || i+1 >= len(runes) || !isNameStart(runes[i+1]) {
+			out.WriteRune(c)
+			continue
+		}
+		j := i + 1
+		for j < len(runes) && isNameChar(runes[j]) {
+			j++
+		}
+		name := string(runes[i+1 : j])
+		value, ok := lookup(name)
+		if !ok {
+			return "", nil, fmt.Errorf("qbs: no value bound for named parameter %q", name)
+		}
+		placeholders, values := namedPlaceholders(value)
+		out.WriteString(placeholders)
+		args = append(args, values...)
+		i = j - 1
+	}
+	return out.String(), args, nil
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
+
+func isNameChar(r rune) bool {
+	return isNameStart(r) || ('0' <= r && r <= '9')
+}
+
+// namedPlaceholders renders the "?" placeholders and flattened arguments
+// for a single bound value, expanding slices into one placeholder per
+// element.
+func namedPlaceholders(value interface{}) (string, []interface{}) {
+	if value == nil {
+		return "?", []interface{}{value}
+	}
+	rv := reflect.ValueOf(value)
+	isByteSlice := rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8
+	if isByteSlice || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return "?", []interface{}{value}
+	}
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", "), values
+}
+
+// namedArgLookup returns a function resolving a ":name" placeholder to its
+// bound value, reading either a map[string]interface{} or the exported
+// fields of a struct, via the same fieldByColumn lookup scanRows uses.
+func namedArgLookup(arg interface{}, mapper func(string) string) (func(name string) (interface{}, bool), error) {
+	if m, ok := arg.(map[string]interface{}); ok {
+		return func(name string) (interface{}, bool) {
+			v, ok := m[name]
+			return v, ok
+		}, nil
+	}
+	rv := reflect.Indirect(reflect.ValueOf(arg))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("qbs: named argument must be a struct or map[string]interface{}, got %T", arg)
+	}
+	return func(name string) (interface{}, bool) {
+		field := fieldByColumn(rv, name, mapper)
+		if !field.IsValid() {
+			return nil, false
+		}
+		return field.Interface(), true
+	}, nil
+}
+
+// columnName returns the explicit column name a struct field was tagged
+// with via `db:"..."` or `qbs:"column=..."`, or "" if untagged.
+func columnName(field reflect.StructField) string {
+	if db := field.Tag.Get("db"); db != "" {
+		return db
+	}
+	qbsTag := field.Tag.Get("qbs")
+	for _, part := range strings.Split(qbsTag, ",") {
+		if strings.HasPrefix(part, "column=") {
+			return strings.TrimPrefix(part, "column=")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package qbs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// titleCaseMapper is a minimal, self-contained mapper used by these tests so
+// they don't depend on the package's default snakeToUpperCamel behavior.
+func titleCaseMapper(column string) string {
+	if column == "" {
+		return column
+	}
+	return strings.ToUpper(column[:1]) + column[1:]
+}
+
+func TestBindNamedMap(t *testing.T) {
+	query, args, err := bindNamed("SELECT * FROM t WHERE id = :id AND name = :name", map[string]interface{}{
+		"id":   1,
+		"name": "bob",
+	}, titleCaseMapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT * FROM t WHERE id = ? AND name = ?"
+	if query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, "bob"}) {
+		t.Errorf("args = %#v", args)
+	}
+}
+
+func TestBindNamedStruct(t *testing.T) {
+	type user struct {
+		Id   int
+		Name string
+	}
+	query, args, err := bindNamed("SELECT * FROM t WHERE id = :id AND name = :name", user{Id: 1, Name: "bob"}, titleCaseMapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT * FROM t WHERE id = ? AND name = ?"
+	if query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, "bob"}) {
+		t.Errorf("args = %#v", args)
+	}
+}
+
+func TestBindNamedSliceExpansion(t *testing.T) {
+	query, args, err := bindNamed("SELECT * FROM t WHERE id IN (:ids)", map[string]interface{}{
+		"ids": []int{1, 2, 3},
+	}, titleCaseMapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT * FROM t WHERE id IN (?, ?, ?)"
+	if query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, 2, 3}) {
+		t.Errorf("args = %#v", args)
+	}
+}
+
+func TestBindNamedMissingValue(t *testing.T) {
+	_, _, err := bindNamed("SELECT * FROM t WHERE id = :id", map[string]interface{}{}, titleCaseMapper)
+	if err == nil {
+		t.Fatal("expected error for unbound named parameter")
+	}
+}
+
+func TestBindNamedIgnoresDoubleColon(t *testing.T) {
+	query, _, err := bindNamed("SELECT id::text FROM t WHERE id = :id", map[string]interface{}{"id": 1}, titleCaseMapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT id::text FROM t WHERE id = ?"
+	if query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+}
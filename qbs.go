@@ -1,6 +1,7 @@
 package qbs
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
@@ -11,12 +12,17 @@ import (
 var connectionPool chan *sql.DB = make(chan *sql.DB, 10)
 
 type Qbs struct {
-	Db           *sql.DB
-	Dialect      Dialect
-	Log          bool
-	Tx           *sql.Tx
-	criteria     *criteria
-	firstTxError error
+	Db               *sql.DB
+	Dialect          Dialect
+	Log              bool
+	Tx               *sql.Tx
+	// NameMapper maps a DB column name to a struct field name, overriding the default snakeToUpperCamel
+	NameMapper       func(string) string
+	criteria         *criteria
+	firstTxError     error
+	ctx              context.Context
+	stmtCacheEnabled bool
+	savepointDepth   int
 }
 
 type Validator interface {
@@ -70,6 +76,28 @@ func (q *Qbs) Begin() {
 	}
 }
 
+// BeginTx is like Begin but accepts a context.Context and sql.TxOptions.
+func (q *Qbs) BeginTx(ctx context.Context, opts *sql.TxOptions) error {
+	if q.Tx != nil {
+		panic("cannot start nested transaction")
+	}
+	tx, err := q.Db.BeginTx(ctx, opts)
+	if err != nil {
+		return q.updateTxError(err)
+	}
+	q.Tx = tx
+	q.ctx = ctx
+	return nil
+}
+
+// currentContext falls back to context.Background() when none was supplied.
+func (q *Qbs) currentContext() context.Context {
+	if q.ctx != nil {
+		return q.ctx
+	}
+	return context.Background()
+}
+
 func (q *Qbs) updateTxError(e error) error {
 	if e != nil {
 		q.log("ERROR: ", e)
@@ -87,6 +115,7 @@ func (q *Qbs) Commit() error {
 	err := q.Tx.Commit()
 	q.updateTxError(err)
 	q.Tx = nil
+	q.ctx = nil
 	return q.firstTxError
 }
 
@@ -94,6 +123,7 @@ func (q *Qbs) Commit() error {
 func (q *Qbs) Rollback() error {
 	err := q.Tx.Rollback()
 	q.Tx = nil
+	q.ctx = nil
 	return q.updateTxError(err)
 }
 
@@ -182,16 +212,20 @@ func (q *Qbs) FindAll(ptrOfSliceOfStructPtr interface{}) error {
 }
 
 func (q *Qbs) doQueryRow(out interface{}, query string, args ...interface{}) error {
+	return q.doQueryRowContext(q.currentContext(), out, query, args...)
+}
+
+func (q *Qbs) doQueryRowContext(ctx context.Context, out interface{}, query string, args ...interface{}) error {
 	defer q.Reset()
 	rowValue := reflect.ValueOf(out)
-	stmt, err := q.Prepare(query)
+	stmt, err := q.PrepareContext(ctx, query)
 	if err != nil {
 		if stmt != nil {
 			stmt.Close()
 		}
 		return q.updateTxError(err)
 	}
-	rows, err := stmt.Query(args...)
+	rows, err := stmt.QueryContext(ctx, args...)
 	defer rows.Close()
 	if err != nil {
 		return q.updateTxError(err)
@@ -208,11 +242,15 @@ func (q *Qbs) doQueryRow(out interface{}, query string, args ...interface{}) err
 }
 
 func (q *Qbs) doQueryRows(out interface{}, query string, args ...interface{}) error {
+	return q.doQueryRowsContext(q.currentContext(), out, query, args...)
+}
+
+func (q *Qbs) doQueryRowsContext(ctx context.Context, out interface{}, query string, args ...interface{}) error {
 	defer q.Reset()
 	sliceValue := reflect.Indirect(reflect.ValueOf(out))
 	sliceType := sliceValue.Type().Elem().Elem()
 	q.log(query, args...)
-	stmt, err := q.Prepare(query)
+	stmt, err := q.PrepareContext(ctx, query)
 	if err != nil {
 		if stmt != nil {
 			stmt.Close()
@@ -220,7 +258,7 @@ func (q *Qbs) doQueryRows(out interface{}, query string, args ...interface{}) er
 		return q.updateTxError(err)
 	}
 
-	rows, err := stmt.Query(args...)
+	rows, err := stmt.QueryContext(ctx, args...)
 	defer rows.Close()
 	if err != nil {
 		return q.updateTxError(err)
@@ -253,13 +291,14 @@ func (q *Qbs) scanRows(rowValue reflect.Value, rows *sql.Rows) (err error) {
 			continue
 		}
 		key := cols[i]
+		mapper := q.nameMapper()
 		paths := strings.Split(key, "___")
 		if len(paths) == 2 {
-			subStruct := rowValue.Elem().FieldByName(snakeToUpperCamel(paths[0]))
+			subStruct := fieldByColumn(rowValue.Elem(), paths[0], mapper)
 			if subStruct.IsNil() {
 				subStruct.Set(reflect.New(subStruct.Type().Elem()))
 			}
-			subField := subStruct.Elem().FieldByName(snakeToUpperCamel(paths[1]))
+			subField := fieldByColumn(subStruct.Elem(), paths[1], mapper)
 			if subField.IsValid() {
 				err = q.Dialect.setModelValue(value, subField)
 				if err != nil {
@@ -267,7 +306,7 @@ func (q *Qbs) scanRows(rowValue reflect.Value, rows *sql.Rows) (err error) {
 				}
 			}
 		} else {
-			field := rowValue.Elem().FieldByName(snakeToUpperCamel(key))
+			field := fieldByColumn(rowValue.Elem(), key, mapper)
 			if field.IsValid() {
 				err = q.Dialect.setModelValue(value, field)
 				if err != nil {
@@ -281,15 +320,22 @@ func (q *Qbs) scanRows(rowValue reflect.Value, rows *sql.Rows) (err error) {
 
 // Same as sql.Db.Exec or sql.Tx.Exec depends on if transaction has began
 func (q *Qbs) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return q.ExecContext(q.currentContext(), query, args...)
+}
+
+// ExecContext is like Exec but accepts a context.Context.
+func (q *Qbs) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	defer q.Reset()
 	query = q.Dialect.substituteMarkers(query)
 	q.log(query, args...)
-	stmt, err := q.Prepare(query)
+	stmt, cached, err := q.prepareContext(ctx, query)
 	if err != nil {
 		return nil, q.updateTxError(err)
 	}
-	defer stmt.Close()
-	result, err := stmt.Exec(args...)
+	if !cached {
+		defer stmt.Close()
+	}
+	result, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return nil, q.updateTxError(err)
 	}
@@ -298,22 +344,32 @@ func (q *Qbs) Exec(query string, args ...interface{}) (sql.Result, error) {
 
 // Same as sql.Db.QueryRow or sql.Tx.QueryRow depends on if transaction has began
 func (q *Qbs) QueryRow(query string, args ...interface{}) *sql.Row {
+	return q.QueryRowContext(q.currentContext(), query, args...)
+}
+
+// QueryRowContext is like QueryRow but accepts a context.Context.
+func (q *Qbs) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	q.log(query, args...)
 	query = q.Dialect.substituteMarkers(query)
 	if q.Tx != nil {
-		return q.Tx.QueryRow(query, args...)
+		return q.Tx.QueryRowContext(ctx, query, args...)
 	}
-	return q.Db.QueryRow(query, args...)
+	return q.Db.QueryRowContext(ctx, query, args...)
 }
 
 // Same as sql.Db.Query or sql.Tx.Query depends on if transaction has began
-func (q *Qbs) Query(query string, args ...interface{}) (rows *sql.Rows,err error) {
+func (q *Qbs) Query(query string, args ...interface{}) (rows *sql.Rows, err error) {
+	return q.QueryContext(q.currentContext(), query, args...)
+}
+
+// QueryContext is like Query but accepts a context.Context.
+func (q *Qbs) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
 	q.log(query, args...)
 	query = q.Dialect.substituteMarkers(query)
 	if q.Tx != nil {
-		rows, err = q.Tx.Query(query, args...)
-	}else{
-		rows, err = q.Db.Query(query, args...)
+		rows, err = q.Tx.QueryContext(ctx, query, args...)
+	} else {
+		rows, err = q.Db.QueryContext(ctx, query, args...)
 	}
 	q.updateTxError(err)
 	return
@@ -321,13 +377,43 @@ func (q *Qbs) Query(query string, args ...interface{}) (rows *sql.Rows,err error
 
 // Same as sql.Db.Prepare or sql.Tx.Prepare depends on if transaction has began
 func (q *Qbs) Prepare(query string) (stmt *sql.Stmt, err error) {
+	return q.PrepareContext(q.currentContext(), query)
+}
+
+// PrepareContext is like Prepare but accepts a context.Context. When a
+// statement cache is enabled via EnableStmtCache, the returned *sql.Stmt
+// may be shared with other callers; the caller must not close it.
+func (q *Qbs) PrepareContext(ctx context.Context, query string) (stmt *sql.Stmt, err error) {
+	stmt, _, err = q.prepareContext(ctx, query)
+	return
+}
+
+// prepareContext prepares query, going through the per-Db statement cache
+// when enabled, and reports whether stmt is a shared one the caller must
+// not close.
+func (q *Qbs) prepareContext(ctx context.Context, query string) (stmt *sql.Stmt, cached bool, err error) {
+	full := query + ";"
 	if q.Tx != nil {
-		stmt, err = q.Tx.Prepare(query + ";")
-	}else{
-		stmt, err = q.Db.Prepare(query + ";")
+		stmt, err = q.Tx.PrepareContext(ctx, full)
+		q.updateTxError(err)
+		return
 	}
-	q.updateTxError(err)
-	return
+	if !q.stmtCacheEnabled {
+		stmt, err = q.Db.PrepareContext(ctx, full)
+		q.updateTxError(err)
+		return
+	}
+	cache := stmtCacheFor(q.Db)
+	if stmt, ok := cache.get(full); ok {
+		return stmt, true, nil
+	}
+	stmt, err = q.Db.PrepareContext(ctx, full)
+	if err != nil {
+		q.updateTxError(err)
+		return nil, false, err
+	}
+	stmt, cached = cache.put(full, stmt)
+	return stmt, cached, nil
 }
 
 // If Id value is not provided, save will insert the record, and the Id value will
@@ -396,6 +482,11 @@ func (q *Qbs) Save(structPtr interface{}) (affected int64, err error) {
 				createdField := structValue.FieldByName(createdModelField.camelName)
 				createdField.Set(reflect.ValueOf(now))
 			}
+			if len(preservedCriteria.returningCols) > 0 {
+				err = q.returningRead(insertReturning, structPtr, preservedCriteria.returningCols)
+			}
+		} else if len(preservedCriteria.returningCols) > 0 {
+			err = q.returningRead(updateReturning, structPtr, preservedCriteria.returningCols)
 		}
 	}
 	return affected, err
@@ -420,7 +511,12 @@ func (q *Qbs) Update(structPtr interface{}) (affected int64, err error) {
 	if q.criteria.condition == nil {
 		panic("Can not update without condition")
 	}
-	return q.Dialect.update(q)
+	returningCols := q.criteria.returningCols
+	affected, err = q.Dialect.update(q)
+	if err == nil && len(returningCols) > 0 {
+		err = q.returningRead(updateReturning, structPtr, returningCols)
+	}
+	return
 }
 
 // The delete condition can be inferred by the Id value of the struct
@@ -432,7 +528,12 @@ func (q *Qbs) Delete(structPtr interface{}) (affected int64, err error) {
 	if q.criteria.condition == nil {
 		panic("Can not delete without condition")
 	}
-	return q.Dialect.delete(q)
+	returningCols := q.criteria.returningCols
+	affected, err = q.Dialect.delete(q)
+	if err == nil && len(returningCols) > 0 {
+		err = q.returningRead(deleteReturning, structPtr, returningCols)
+	}
+	return
 }
 
 // This method can be used to validate unique column before trying to save
@@ -457,6 +558,7 @@ func (q *Qbs) Close() error{
 			return nil
 		default:
 		}
+		closeStmtCache(q.Db)
 		return q.Db.Close()
 	}
 	return nil
@@ -0,0 +1,42 @@
+package qbs
+
+import "context"
+
+// withContext runs fn with ctx set as the current context, restoring the
+// previous value afterwards so the scope doesn't leak into later calls.
+func (q *Qbs) withContext(ctx context.Context, fn func()) {
+	prev := q.ctx
+	q.ctx = ctx
+	defer func() { q.ctx = prev }()
+	fn()
+}
+
+// FindContext is like Find but accepts a context.Context.
+func (q *Qbs) FindContext(ctx context.Context, structPtr interface{}) (err error) {
+	q.withContext(ctx, func() { err = q.Find(structPtr) })
+	return
+}
+
+// FindAllContext is like FindAll but accepts a context.Context.
+func (q *Qbs) FindAllContext(ctx context.Context, ptrOfSliceOfStructPtr interface{}) (err error) {
+	q.withContext(ctx, func() { err = q.FindAll(ptrOfSliceOfStructPtr) })
+	return
+}
+
+// SaveContext is like Save but accepts a context.Context.
+func (q *Qbs) SaveContext(ctx context.Context, structPtr interface{}) (affected int64, err error) {
+	q.withContext(ctx, func() { affected, err = q.Save(structPtr) })
+	return
+}
+
+// UpdateContext is like Update but accepts a context.Context.
+func (q *Qbs) UpdateContext(ctx context.Context, structPtr interface{}) (affected int64, err error) {
+	q.withContext(ctx, func() { affected, err = q.Update(structPtr) })
+	return
+}
+
+// DeleteContext is like Delete but accepts a context.Context.
+func (q *Qbs) DeleteContext(ctx context.Context, structPtr interface{}) (affected int64, err error) {
+	q.withContext(ctx, func() { affected, err = q.Delete(structPtr) })
+	return
+}
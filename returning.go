@@ -0,0 +1,75 @@
+package qbs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// returningOp identifies which statement Returning() should attach to.
+type returningOp int
+
+const (
+	insertReturning returningOp = iota
+	updateReturning
+	deleteReturning
+)
+
+// ReturningDialect is implemented by dialects whose INSERT/UPDATE/DELETE
+// support a RETURNING clause. Whenever Returning has been called on the Qbs,
+// Save/Update/Delete call the matching method after their statement has run
+// and scan the column values it reports into the struct, instead of relying
+// solely on LastInsertId. None of this package's dialects implement it.
+type ReturningDialect interface {
+	insertReturning(q *Qbs, cols []string) (map[string]interface{}, error)
+	updateReturning(q *Qbs, cols []string) (map[string]interface{}, error)
+	deleteReturning(q *Qbs, cols []string) (map[string]interface{}, error)
+}
+
+// Returning declares extra columns that should be read back from the
+// database after the next Save/Update/Delete. On a dialect that implements
+// ReturningDialect, Save/Update/Delete ask it for those columns after their
+// statement has run and scan the reported values straight back into the
+// struct. On dialects that don't implement it, which is every dialect in
+// this package, Save/Update/Delete run their statement and then return an
+// error rather than silently dropping the requested columns.
+func (q *Qbs) Returning(cols ...string) *Qbs {
+	q.criteria.returningCols = cols
+	return q
+}
+
+// returningRead runs the RETURNING read-back for op, scanning the reported
+// columns into structPtr.
+func (q *Qbs) returningRead(op returningOp, structPtr interface{}, cols []string) error {
+	rd, ok := q.Dialect.(ReturningDialect)
+	if !ok {
+		return fmt.Errorf("qbs: %T does not implement ReturningDialect, Returning(%v) has no effect", q.Dialect, cols)
+	}
+	var values map[string]interface{}
+	var err error
+	switch op {
+	case updateReturning:
+		values, err = rd.updateReturning(q, cols)
+	case deleteReturning:
+		values, err = rd.deleteReturning(q, cols)
+	default:
+		values, err = rd.insertReturning(q, cols)
+	}
+	if err != nil {
+		return err
+	}
+	structValue := reflect.Indirect(reflect.ValueOf(structPtr))
+	mapper := q.nameMapper()
+	for col, value := range values {
+		if value == nil {
+			continue
+		}
+		field := fieldByColumn(structValue, col, mapper)
+		if !field.IsValid() {
+			continue
+		}
+		if err = q.Dialect.setModelValue(reflect.Indirect(reflect.ValueOf(&value)), field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
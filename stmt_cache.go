@@ -0,0 +1,100 @@
+package qbs
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// defaultStmtCacheLimit bounds how many distinct SQL strings a stmtCache
+// will hold. Without a cap, high-cardinality queries (e.g. a Filter
+// "__in" or NamedExec slice that renders a different placeholder count per
+// call) would grow the cache, and its prepared statements, without bound.
+const defaultStmtCacheLimit = 500
+
+// stmtCache caches prepared statements keyed by their final SQL string. A
+// cache is tied to a *sql.DB and shared by every Qbs wrapping it; it is
+// never consulted while a Qbs is inside a transaction, since prepared
+// statements cannot cross transactions.
+type stmtCache struct {
+	mu    sync.RWMutex
+	stmts map[string]*sql.Stmt
+	limit int
+}
+
+func newStmtCache() *stmtCache {
+	return &stmtCache{stmts: make(map[string]*sql.Stmt), limit: defaultStmtCacheLimit}
+}
+
+func (c *stmtCache) get(query string) (*sql.Stmt, bool) {
+	c.mu.RLock()
+	stmt, ok := c.stmts[query]
+	c.mu.RUnlock()
+	return stmt, ok
+}
+
+// put registers stmt under query and returns the statement the caller
+// should use, reporting whether that statement is held by the cache (so the
+// caller must not close it). If another goroutine raced us and already
+// cached one for the same query, stmt is closed and the existing, cached one
+// is returned instead. If the cache is already at its limit, stmt is handed
+// back uncached, leaving the caller responsible for closing it.
+func (c *stmtCache) put(query string, stmt *sql.Stmt) (*sql.Stmt, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.stmts[query]; ok {
+		stmt.Close()
+		return existing, true
+	}
+	if len(c.stmts) >= c.limit {
+		return stmt, false
+	}
+	c.stmts[query] = stmt
+	return stmt, true
+}
+
+func (c *stmtCache) closeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for query, stmt := range c.stmts {
+		stmt.Close()
+		delete(c.stmts, query)
+	}
+}
+
+var (
+	stmtCachesMu sync.Mutex
+	stmtCaches   = make(map[*sql.DB]*stmtCache)
+)
+
+func stmtCacheFor(db *sql.DB) *stmtCache {
+	stmtCachesMu.Lock()
+	defer stmtCachesMu.Unlock()
+	c, ok := stmtCaches[db]
+	if !ok {
+		c = newStmtCache()
+		stmtCaches[db] = c
+	}
+	return c
+}
+
+func closeStmtCache(db *sql.DB) {
+	stmtCachesMu.Lock()
+	c, ok := stmtCaches[db]
+	if ok {
+		delete(stmtCaches, db)
+	}
+	stmtCachesMu.Unlock()
+	if ok {
+		c.closeAll()
+	}
+}
+
+// EnableStmtCache turns on a per-Db prepared-statement cache, bounded to
+// defaultStmtCacheLimit entries, so repeated Find/Save/Exec calls with the
+// same rendered SQL reuse one *sql.Stmt instead of re-preparing it every
+// time. It is bypassed while the Qbs is inside a transaction. Disable it
+// for drivers or poolers (e.g. pgbouncer in transaction-pooling mode) that
+// don't support server-side prepared statements.
+func (q *Qbs) EnableStmtCache(enabled bool) {
+	q.stmtCacheEnabled = enabled
+}
@@ -0,0 +1,99 @@
+package qbs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, nil }
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                                   { return nil }
+func (fakeStmt) NumInput() int                                  { return -1 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) { return driver.ResultNoRows, nil }
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error) { return nil, sql.ErrNoRows }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+func init() {
+	sql.Register("qbs-stmtcache-fake", fakeDriver{})
+}
+
+func newFakeStmt(t *testing.T) *sql.Stmt {
+	t.Helper()
+	db, err := sql.Open("qbs-stmtcache-fake", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	stmt, err := db.Prepare("SELECT 1")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	return stmt
+}
+
+func TestStmtCachePutGet(t *testing.T) {
+	c := newStmtCache()
+	stmt := newFakeStmt(t)
+
+	got, cached := c.put("SELECT 1;", stmt)
+	if !cached || got != stmt {
+		t.Fatalf("put() = %v, %v; want %v, true", got, cached, stmt)
+	}
+	if got, ok := c.get("SELECT 1;"); !ok || got != stmt {
+		t.Fatalf("get() = %v, %v; want %v, true", got, ok, stmt)
+	}
+	if _, ok := c.get("SELECT 2;"); ok {
+		t.Fatal("get() found a statement for a query that was never cached")
+	}
+}
+
+func TestStmtCachePutRace(t *testing.T) {
+	c := newStmtCache()
+	first := newFakeStmt(t)
+	second := newFakeStmt(t)
+
+	c.put("SELECT 1;", first)
+	got, cached := c.put("SELECT 1;", second)
+	if !cached || got != first {
+		t.Fatalf("put() on existing key = %v, %v; want the original statement, true", got, cached)
+	}
+}
+
+func TestStmtCacheRespectsLimit(t *testing.T) {
+	c := newStmtCache()
+	c.limit = 1
+
+	c.put("SELECT 1;", newFakeStmt(t))
+	overflow := newFakeStmt(t)
+	got, cached := c.put("SELECT 2;", overflow)
+	if cached || got != overflow {
+		t.Fatalf("put() past limit = %v, %v; want uncached overflow statement", got, cached)
+	}
+	if _, ok := c.get("SELECT 2;"); ok {
+		t.Fatal("get() found a statement that should have been rejected by the limit")
+	}
+}
+
+func TestStmtCacheCloseAll(t *testing.T) {
+	c := newStmtCache()
+	c.put("SELECT 1;", newFakeStmt(t))
+	c.put("SELECT 2;", newFakeStmt(t))
+
+	c.closeAll()
+
+	if _, ok := c.get("SELECT 1;"); ok {
+		t.Fatal("get() found a statement after closeAll")
+	}
+	if len(c.stmts) != 0 {
+		t.Fatalf("len(c.stmts) = %d; want 0 after closeAll", len(c.stmts))
+	}
+}
@@ -0,0 +1,56 @@
+package qbs
+
+import "fmt"
+
+// InTx runs fn inside a transaction on q: it begins one, invokes fn, and
+// commits on a nil return or rolls back otherwise, re-panicking after
+// rollback if fn panics. This removes the class of bugs around manually
+// calling Begin/Commit/Rollback and forgetting to check firstTxError or to
+// roll back on an early return.
+//
+// If q is already inside a transaction, InTx issues a SAVEPOINT instead of
+// a real nested transaction (which database/sql does not support), giving
+// pseudo-nested transactions: the nested call's fn is released with
+// RELEASE SAVEPOINT on success or undone with ROLLBACK TO SAVEPOINT on
+// failure, without aborting the outer transaction.
+func (q *Qbs) InTx(fn func(*Qbs) error) (err error) {
+	if q.Tx != nil {
+		return q.inSavepoint(fn)
+	}
+	q.Begin()
+	defer func() {
+		if p := recover(); p != nil {
+			q.Rollback()
+			panic(p)
+		}
+	}()
+	if err = fn(q); err != nil {
+		q.Rollback()
+		return err
+	}
+	return q.Commit()
+}
+
+func (q *Qbs) inSavepoint(fn func(*Qbs) error) (err error) {
+	q.savepointDepth++
+	name := fmt.Sprintf("sp_%d", q.savepointDepth)
+	if _, err = q.Exec("SAVEPOINT " + name); err != nil {
+		q.savepointDepth--
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			q.Exec("ROLLBACK TO SAVEPOINT " + name)
+			q.savepointDepth--
+			panic(p)
+		}
+	}()
+	if err = fn(q); err != nil {
+		q.Exec("ROLLBACK TO SAVEPOINT " + name)
+		q.savepointDepth--
+		return err
+	}
+	_, err = q.Exec("RELEASE SAVEPOINT " + name)
+	q.savepointDepth--
+	return err
+}
@@ -0,0 +1,88 @@
+package qbs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+type txFakeTx struct{ log *[]string }
+
+func (t txFakeTx) Commit() error   { *t.log = append(*t.log, "commit"); return nil }
+func (t txFakeTx) Rollback() error { *t.log = append(*t.log, "rollback"); return nil }
+
+type txFakeConn struct{ log *[]string }
+
+func (c txFakeConn) Prepare(query string) (driver.Stmt, error) { return txFakeStmt{}, nil }
+func (c txFakeConn) Close() error                              { return nil }
+func (c txFakeConn) Begin() (driver.Tx, error)                 { return txFakeTx{log: c.log}, nil }
+
+type txFakeStmt struct{}
+
+func (txFakeStmt) Close() error                                  { return nil }
+func (txFakeStmt) NumInput() int                                 { return -1 }
+func (txFakeStmt) Exec(args []driver.Value) (driver.Result, error) { return driver.ResultNoRows, nil }
+func (txFakeStmt) Query(args []driver.Value) (driver.Rows, error) { return nil, sql.ErrNoRows }
+
+type txFakeDriver struct{ log *[]string }
+
+func (d txFakeDriver) Open(name string) (driver.Conn, error) { return txFakeConn{log: d.log}, nil }
+
+func init() {
+	sql.Register("qbs-tx-fake", txFakeDriver{log: &txFakeTxLog})
+}
+
+var txFakeTxLog []string
+
+func newTxFakeQbs(t *testing.T) *Qbs {
+	t.Helper()
+	txFakeTxLog = nil
+	db, err := sql.Open("qbs-tx-fake", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	q := &Qbs{Db: db}
+	q.Reset()
+	return q
+}
+
+func TestInTxCommitsOnSuccess(t *testing.T) {
+	q := newTxFakeQbs(t)
+	err := q.InTx(func(tx *Qbs) error { return nil })
+	if err != nil {
+		t.Fatalf("InTx() = %v, want nil", err)
+	}
+	if len(txFakeTxLog) != 1 || txFakeTxLog[0] != "commit" {
+		t.Fatalf("txFakeTxLog = %v, want [commit]", txFakeTxLog)
+	}
+	if q.Tx != nil {
+		t.Fatal("q.Tx should be nil after commit")
+	}
+}
+
+func TestInTxRollsBackOnError(t *testing.T) {
+	q := newTxFakeQbs(t)
+	wantErr := errors.New("boom")
+	err := q.InTx(func(tx *Qbs) error { return wantErr })
+	if err != wantErr {
+		t.Fatalf("InTx() = %v, want %v", err, wantErr)
+	}
+	if len(txFakeTxLog) != 1 || txFakeTxLog[0] != "rollback" {
+		t.Fatalf("txFakeTxLog = %v, want [rollback]", txFakeTxLog)
+	}
+}
+
+func TestInTxRollsBackAndRepanicsOnPanic(t *testing.T) {
+	q := newTxFakeQbs(t)
+	defer func() {
+		p := recover()
+		if p != "boom" {
+			t.Fatalf("recover() = %v, want %q", p, "boom")
+		}
+		if len(txFakeTxLog) != 1 || txFakeTxLog[0] != "rollback" {
+			t.Fatalf("txFakeTxLog = %v, want [rollback]", txFakeTxLog)
+		}
+	}()
+	q.InTx(func(tx *Qbs) error { panic("boom") })
+}